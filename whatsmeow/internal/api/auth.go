@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"whatsmeow-service/internal/webhook"
+	"whatsmeow-service/internal/whatsapp"
+)
+
+type contextKey int
+
+// authenticatedInstanceKey holds the instance ID whose own API key authenticated a
+// request, so handlers can enforce ownership on resources the route itself doesn't
+// scope to an instance. Absent when the request came in on the global admin token.
+const authenticatedInstanceKey contextKey = iota
+
+// authenticatedInstance returns the instance ID an API key authenticated this request
+// as, and false if it was authenticated via the global admin token instead
+func authenticatedInstance(r *http.Request) (string, bool) {
+	id, ok := r.Context().Value(authenticatedInstanceKey).(string)
+	return id, ok
+}
+
+// bearerToken extracts the token from "Authorization: Bearer <token>", falling back to
+// a "token" query parameter so WebSocket clients that can't set headers can still authenticate
+func bearerToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return token
+		}
+	}
+	return r.URL.Query().Get("token")
+}
+
+// requestInstanceID resolves the instance a request targets, first from the route
+// variables (/instances/{id}/...) and, failing that, from an "instanceId" field in the
+// JSON body (the message-sending routes carry it there instead). The body is restored
+// afterwards so the real handler can still decode it.
+func requestInstanceID(r *http.Request) string {
+	vars := mux.Vars(r)
+	if id := vars["id"]; id != "" {
+		return id
+	}
+	if id := vars["instanceId"]; id != "" {
+		return id
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var payload struct {
+		InstanceID string `json:"instanceId"`
+	}
+	_ = json.Unmarshal(body, &payload)
+	return payload.InstanceID
+}
+
+// AuthMiddleware requires a bearer token matching either the global admin token
+// or the target instance's own API key on every request it wraps
+func AuthMiddleware(manager *whatsapp.Manager, adminToken string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := bearerToken(r)
+			if token == "" {
+				errorResponse(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+
+			if adminToken != "" && token == adminToken {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			instanceID := requestInstanceID(r)
+			if instanceID != "" && manager.ValidateAPIKey(instanceID, token) {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authenticatedInstanceKey, instanceID)))
+				return
+			}
+
+			errorResponse(w, http.StatusUnauthorized, "invalid bearer token")
+		})
+	}
+}
+
+// WebhookAuthMiddleware requires a bearer token matching either the global admin token
+// or the API key of the instance that owns the targeted webhook subscription. The route
+// variable on these routes (webhookId/id) identifies a webhook subscription, not an
+// instance, so the owning instance is resolved through the dispatcher instead of the
+// generic route-var heuristic AuthMiddleware uses.
+func WebhookAuthMiddleware(manager *whatsapp.Manager, webhooks *webhook.Dispatcher, adminToken string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := bearerToken(r)
+			if token == "" {
+				errorResponse(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+
+			if adminToken != "" && token == adminToken {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			vars := mux.Vars(r)
+			webhookID := vars["webhookId"]
+			if webhookID == "" {
+				webhookID = vars["id"]
+			}
+
+			sub, ok := webhooks.Get(webhookID)
+			if !ok {
+				errorResponse(w, http.StatusNotFound, "webhook not found")
+				return
+			}
+
+			if manager.ValidateAPIKey(sub.InstanceID, token) {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authenticatedInstanceKey, sub.InstanceID)))
+				return
+			}
+
+			errorResponse(w, http.StatusUnauthorized, "invalid bearer token")
+		})
+	}
+}
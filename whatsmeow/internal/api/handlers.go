@@ -2,30 +2,44 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 
+	"whatsmeow-service/internal/history"
+	"whatsmeow-service/internal/media"
+	"whatsmeow-service/internal/webhook"
 	"whatsmeow-service/internal/whatsapp"
 )
 
 // Handlers contains HTTP handlers
 type Handlers struct {
 	manager  *whatsapp.Manager
+	webhooks *webhook.Dispatcher
+	media    *media.Store
+	history  *history.Store
 	upgrader websocket.Upgrader
 }
 
-// NewHandlers creates new handlers
-func NewHandlers(manager *whatsapp.Manager) *Handlers {
+// NewHandlers creates new handlers. allowedOrigins is consulted by the WebSocket
+// upgrader's CheckOrigin; an empty list allows every origin.
+func NewHandlers(manager *whatsapp.Manager, webhooks *webhook.Dispatcher, mediaStore *media.Store, historyStore *history.Store, allowedOrigins []string) *Handlers {
 	return &Handlers{
-		manager: manager,
+		manager:  manager,
+		webhooks: webhooks,
+		media:    mediaStore,
+		history:  historyStore,
 		upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				return true // Allow all origins
-			},
+			CheckOrigin:     originChecker(allowedOrigins),
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
 		},
@@ -171,6 +185,42 @@ func (h *Handlers) GetQRCode(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// PairInstanceRequest represents a pairing-code login request
+type PairInstanceRequest struct {
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+// PairInstance starts a phone-number pairing flow and returns the code to enter on the phone
+func (h *Handlers) PairInstance(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+
+	var req PairInstanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !whatsapp.ValidE164(req.PhoneNumber) {
+		errorResponse(w, http.StatusBadRequest, "phoneNumber must be in E.164 format, e.g. +15551234567")
+		return
+	}
+
+	log.Info().Str("instanceId", instanceID).Msg("Requesting pairing code")
+
+	code, err := h.manager.PairPhone(instanceID, req.PhoneNumber)
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to request pairing code")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"pairingCode": code,
+		"message":     "Enter this code on your phone under Linked Devices",
+	})
+}
+
 // ============================================
 // Message Handlers
 // ============================================
@@ -220,11 +270,15 @@ func (h *Handlers) SendTextMessage(w http.ResponseWriter, r *http.Request) {
 
 // SendMediaRequest represents media message request
 type SendMediaRequest struct {
-	InstanceID string `json:"instanceId"`
-	To         string `json:"to"`
-	MediaURL   string `json:"mediaUrl"`
-	Caption    string `json:"caption,omitempty"`
-	MediaType  string `json:"mediaType,omitempty"` // image, video, audio, document
+	InstanceID        string `json:"instanceId"`
+	To                string `json:"to"`
+	MediaURL          string `json:"mediaUrl"`
+	Caption           string `json:"caption,omitempty"`
+	MediaType         string `json:"mediaType,omitempty"` // image, video, audio, document
+	QuotedMessageID   string `json:"quotedMessageId,omitempty"`
+	QuotedFromMe      bool   `json:"quotedFromMe,omitempty"`
+	QuotedParticipant string `json:"quotedParticipant,omitempty"`
+	QuotedText        string `json:"quotedText,omitempty"`
 }
 
 // SendMediaMessage sends media message
@@ -250,7 +304,17 @@ func (h *Handlers) SendMediaMessage(w http.ResponseWriter, r *http.Request) {
 		Str("mediaType", mediaType).
 		Msg("Sending media message")
 
-	msgID, err := h.manager.SendMediaMessage(req.InstanceID, to, req.MediaURL, req.Caption, mediaType)
+	var quoted *whatsapp.QuotedMessage
+	if req.QuotedMessageID != "" {
+		quoted = &whatsapp.QuotedMessage{
+			MessageID:   req.QuotedMessageID,
+			FromMe:      req.QuotedFromMe,
+			Participant: req.QuotedParticipant,
+			Text:        req.QuotedText,
+		}
+	}
+
+	msgID, err := h.manager.SendMediaMessage(req.InstanceID, to, req.MediaURL, req.Caption, mediaType, quoted)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to send media message")
 		errorResponse(w, http.StatusInternalServerError, err.Error())
@@ -307,14 +371,15 @@ func (h *Handlers) SendPresence(w http.ResponseWriter, r *http.Request) {
 
 // SendLocationRequest represents location message request
 type SendLocationRequest struct {
-	InstanceID  string  `json:"instanceId"`
-	To          string  `json:"to"`
-	Latitude    float64 `json:"latitude"`
-	Longitude   float64 `json:"longitude"`
-	Description string  `json:"description,omitempty"`
+	InstanceID string  `json:"instanceId"`
+	To         string  `json:"to"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Name       string  `json:"name,omitempty"`
+	Address    string  `json:"address,omitempty"`
 }
 
-// SendLocationMessage sends location message
+// SendLocationMessage sends a location pin
 func (h *Handlers) SendLocationMessage(w http.ResponseWriter, r *http.Request) {
 	var req SendLocationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -322,8 +387,188 @@ func (h *Handlers) SendLocationMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: Implement location sending
-	errorResponse(w, http.StatusNotImplemented, "Location sending not yet implemented")
+	if req.InstanceID == "" || req.To == "" {
+		errorResponse(w, http.StatusBadRequest, "instanceId and to are required")
+		return
+	}
+
+	to := cleanPhoneNumber(req.To)
+
+	msgID, err := h.manager.SendLocationMessage(req.InstanceID, to, req.Latitude, req.Longitude, req.Name, req.Address)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send location message")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"messageId": msgID,
+		"to":        to,
+		"status":    "sent",
+	})
+}
+
+// SendContactRequest represents a contact-card message request
+type SendContactRequest struct {
+	InstanceID  string `json:"instanceId"`
+	To          string `json:"to"`
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+// SendContactMessage sends a vCard contact card
+func (h *Handlers) SendContactMessage(w http.ResponseWriter, r *http.Request) {
+	var req SendContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.InstanceID == "" || req.To == "" || req.Name == "" || req.PhoneNumber == "" {
+		errorResponse(w, http.StatusBadRequest, "instanceId, to, name, and phoneNumber are required")
+		return
+	}
+
+	to := cleanPhoneNumber(req.To)
+
+	msgID, err := h.manager.SendContactMessage(req.InstanceID, to, whatsapp.ContactCard{
+		Name:        req.Name,
+		PhoneNumber: cleanPhoneNumber(req.PhoneNumber),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send contact message")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"messageId": msgID,
+		"to":        to,
+		"status":    "sent",
+	})
+}
+
+// SendButtonsRequest represents a buttons message request
+type SendButtonsRequest struct {
+	InstanceID string            `json:"instanceId"`
+	To         string            `json:"to"`
+	Text       string            `json:"text"`
+	Footer     string            `json:"footer,omitempty"`
+	Buttons    []whatsapp.Button `json:"buttons"`
+}
+
+// SendButtonsMessage sends text with up to 3 quick-reply buttons
+func (h *Handlers) SendButtonsMessage(w http.ResponseWriter, r *http.Request) {
+	var req SendButtonsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.InstanceID == "" || req.To == "" || req.Text == "" {
+		errorResponse(w, http.StatusBadRequest, "instanceId, to, and text are required")
+		return
+	}
+
+	to := cleanPhoneNumber(req.To)
+
+	msgID, err := h.manager.SendButtonsMessage(req.InstanceID, to, req.Text, req.Footer, req.Buttons)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send buttons message")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"messageId": msgID,
+		"to":        to,
+		"status":    "sent",
+	})
+}
+
+// SendListRequest represents a list message request
+type SendListRequest struct {
+	InstanceID string                 `json:"instanceId"`
+	To         string                 `json:"to"`
+	Title      string                 `json:"title"`
+	Text       string                 `json:"text"`
+	Footer     string                 `json:"footer,omitempty"`
+	ButtonText string                 `json:"buttonText"`
+	Sections   []whatsapp.ListSection `json:"sections"`
+}
+
+// SendListMessage sends a list of selectable rows grouped into sections
+func (h *Handlers) SendListMessage(w http.ResponseWriter, r *http.Request) {
+	var req SendListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.InstanceID == "" || req.To == "" || req.ButtonText == "" {
+		errorResponse(w, http.StatusBadRequest, "instanceId, to, and buttonText are required")
+		return
+	}
+
+	to := cleanPhoneNumber(req.To)
+
+	msgID, err := h.manager.SendListMessage(req.InstanceID, to, req.Title, req.Text, req.Footer, req.ButtonText, req.Sections)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send list message")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"messageId": msgID,
+		"to":        to,
+		"status":    "sent",
+	})
+}
+
+// SendReplyRequest represents a quoted-reply text message request
+type SendReplyRequest struct {
+	InstanceID        string `json:"instanceId"`
+	To                string `json:"to"`
+	Text              string `json:"text"`
+	QuotedMessageID   string `json:"quotedMessageId"`
+	QuotedFromMe      bool   `json:"quotedFromMe"`
+	QuotedParticipant string `json:"quotedParticipant,omitempty"`
+	QuotedText        string `json:"quotedText,omitempty"`
+}
+
+// SendReplyMessage sends a text message quoting an earlier message
+func (h *Handlers) SendReplyMessage(w http.ResponseWriter, r *http.Request) {
+	var req SendReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.InstanceID == "" || req.To == "" || req.Text == "" || req.QuotedMessageID == "" {
+		errorResponse(w, http.StatusBadRequest, "instanceId, to, text, and quotedMessageId are required")
+		return
+	}
+
+	to := cleanPhoneNumber(req.To)
+
+	msgID, err := h.manager.SendReplyMessage(req.InstanceID, to, req.Text, whatsapp.QuotedMessage{
+		MessageID:   req.QuotedMessageID,
+		FromMe:      req.QuotedFromMe,
+		Participant: req.QuotedParticipant,
+		Text:        req.QuotedText,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send reply message")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"messageId": msgID,
+		"to":        to,
+		"status":    "sent",
+	})
 }
 
 // ============================================
@@ -332,20 +577,69 @@ func (h *Handlers) SendLocationMessage(w http.ResponseWriter, r *http.Request) {
 
 // GetContacts gets contacts for instance
 func (h *Handlers) GetContacts(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement
-	errorResponse(w, http.StatusNotImplemented, "Not yet implemented")
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+
+	contacts, err := h.manager.GetContacts(instanceID)
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to get contacts")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"contacts": contacts,
+	})
 }
 
-// CheckNumber checks if number is on WhatsApp
+// CheckNumberRequest represents a batch IsOnWhatsApp lookup request
+type CheckNumberRequest struct {
+	Numbers []string `json:"numbers"`
+}
+
+// CheckNumber checks which of the given numbers are on WhatsApp
 func (h *Handlers) CheckNumber(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement
-	errorResponse(w, http.StatusNotImplemented, "Not yet implemented")
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+
+	var req CheckNumberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.Numbers) == 0 {
+		errorResponse(w, http.StatusBadRequest, "numbers is required")
+		return
+	}
+
+	results, err := h.manager.CheckNumbers(instanceID, req.Numbers)
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to check numbers")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"results": results,
+	})
 }
 
 // GetGroups gets groups for instance
 func (h *Handlers) GetGroups(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement
-	errorResponse(w, http.StatusNotImplemented, "Not yet implemented")
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+
+	groups, err := h.manager.GetGroups(instanceID)
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to get groups")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"groups": groups,
+	})
 }
 
 // ============================================
@@ -430,6 +724,225 @@ func (h *Handlers) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// RotateAPIKey generates a new API key for an instance, invalidating the old one
+func (h *Handlers) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+
+	key, err := h.manager.RotateAPIKey(instanceID)
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to rotate API key")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]string{
+		"apiKey": key,
+	})
+}
+
+// ============================================
+// Chat History Handlers
+// ============================================
+
+// GetChats returns a page of chats ordered by most recent activity
+func (h *Handlers) GetChats(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+
+	limit := parseLimit(r.URL.Query().Get("limit"), 50)
+	cursor := r.URL.Query().Get("cursor")
+
+	chats, err := h.history.ListChats(instanceID, limit, cursor)
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to list chats")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	nextCursor := ""
+	if len(chats) == limit {
+		nextCursor = chats[len(chats)-1].JID
+	}
+
+	successResponse(w, map[string]interface{}{
+		"chats":      chats,
+		"nextCursor": nextCursor,
+	})
+}
+
+// GetChatMessages returns a page of messages for one chat, newest first
+func (h *Handlers) GetChatMessages(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+	chatJID := vars["jid"]
+
+	limit := parseLimit(r.URL.Query().Get("limit"), 50)
+	var before int64
+	if v := r.URL.Query().Get("before"); v != "" {
+		before, _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	messages, err := h.history.ListMessages(instanceID, chatJID, before, limit)
+	if err != nil {
+		log.Error().Err(err).Str("instanceId", instanceID).Msg("Failed to list chat messages")
+		errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"messages": messages,
+	})
+}
+
+func parseLimit(raw string, fallback int) int {
+	if raw == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
+// ============================================
+// Media Handler
+// ============================================
+
+// GetMedia streams a previously auto-downloaded attachment, supporting Range requests
+func (h *Handlers) GetMedia(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["instanceId"]
+	messageID := vars["messageId"]
+
+	path, err := h.media.Find(instanceID, messageID)
+	if err != nil {
+		errorResponse(w, http.StatusNotFound, "Media not found")
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, "Failed to open media file")
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, "Failed to stat media file")
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), file)
+}
+
+// ============================================
+// Webhook Handlers
+// ============================================
+
+// RegisterWebhookRequest represents a webhook registration request
+type RegisterWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events,omitempty"`
+}
+
+// RegisterWebhook registers a new webhook URL for an instance
+func (h *Handlers) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+
+	var req RegisterWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.URL == "" || req.Secret == "" {
+		errorResponse(w, http.StatusBadRequest, "url and secret are required")
+		return
+	}
+
+	parsed, err := url.Parse(req.URL)
+	if err != nil || parsed.Scheme != "https" {
+		errorResponse(w, http.StatusBadRequest, "url must be an https URL")
+		return
+	}
+
+	sub := h.webhooks.Register(instanceID, req.URL, req.Secret, req.Events)
+
+	successResponse(w, sub)
+}
+
+// ListWebhooks lists every webhook registered for an instance
+func (h *Handlers) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	instanceID := vars["id"]
+
+	successResponse(w, map[string]interface{}{
+		"webhooks": h.webhooks.List(instanceID),
+	})
+}
+
+// DeleteWebhook removes a webhook subscription
+func (h *Handlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhookID := vars["webhookId"]
+
+	if !h.authorizedForWebhook(r, w, webhookID) {
+		return
+	}
+
+	if err := h.webhooks.Remove(webhookID); err != nil {
+		errorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	successResponse(w, map[string]string{
+		"message": "Webhook removed successfully",
+	})
+}
+
+// GetWebhookDeliveries returns the delivery attempt history for a webhook, for debugging
+func (h *Handlers) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhookID := vars["id"]
+
+	if !h.authorizedForWebhook(r, w, webhookID) {
+		return
+	}
+
+	successResponse(w, map[string]interface{}{
+		"deliveries": h.webhooks.Deliveries(webhookID),
+	})
+}
+
+// authorizedForWebhook reports whether the request may act on webhookID, writing the
+// error response itself and returning false if not: the webhook must exist, and if the
+// caller authenticated with a per-instance API key (rather than the admin token), that
+// instance must be the one the webhook was registered for.
+func (h *Handlers) authorizedForWebhook(r *http.Request, w http.ResponseWriter, webhookID string) bool {
+	sub, ok := h.webhooks.Get(webhookID)
+	if !ok {
+		errorResponse(w, http.StatusNotFound, fmt.Sprintf("webhook %s not found", webhookID))
+		return false
+	}
+
+	if instanceID, scoped := authenticatedInstance(r); scoped && instanceID != sub.InstanceID {
+		errorResponse(w, http.StatusForbidden, "webhook belongs to a different instance")
+		return false
+	}
+
+	return true
+}
+
 // ============================================
 // Helpers
 // ============================================
@@ -443,3 +956,20 @@ func cleanPhoneNumber(number string) string {
 	}
 	return result
 }
+
+// originChecker builds a websocket.Upgrader.CheckOrigin func that only allows origins in
+// allowed; an empty list allows every origin (useful for local development)
+func originChecker(allowed []string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		if len(allowed) == 0 {
+			return true
+		}
+		origin := r.Header.Get("Origin")
+		for _, a := range allowed {
+			if a == origin {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"whatsmeow-service/internal/whatsapp"
+)
+
+// NewRouter builds the HTTP mux wiring every instance, message, and WebSocket route.
+// adminToken is the global bearer token accepted in addition to each instance's own API key.
+func NewRouter(h *Handlers, manager *whatsapp.Manager, adminToken string) *mux.Router {
+	r := mux.NewRouter()
+	auth := AuthMiddleware(manager, adminToken)
+
+	instances := r.PathPrefix("/instances/{id}").Subrouter()
+	instances.Use(auth)
+	instances.HandleFunc("/connect", h.ConnectInstance).Methods("POST")
+	instances.HandleFunc("/disconnect", h.DisconnectInstance).Methods("POST")
+	instances.HandleFunc("/logout", h.LogoutInstance).Methods("POST")
+	instances.HandleFunc("/status", h.GetInstanceStatus).Methods("GET")
+	instances.HandleFunc("/qrcode", h.GetQRCode).Methods("GET")
+	instances.HandleFunc("/pair", h.PairInstance).Methods("POST")
+	instances.HandleFunc("/contacts", h.GetContacts).Methods("GET")
+	instances.HandleFunc("/groups", h.GetGroups).Methods("GET")
+	instances.HandleFunc("/check-number", h.CheckNumber).Methods("POST")
+	instances.HandleFunc("/webhooks", h.RegisterWebhook).Methods("POST")
+	instances.HandleFunc("/webhooks", h.ListWebhooks).Methods("GET")
+	instances.HandleFunc("/apikey/rotate", h.RotateAPIKey).Methods("POST")
+	instances.HandleFunc("/chats", h.GetChats).Methods("GET")
+	instances.HandleFunc("/chats/{jid}/messages", h.GetChatMessages).Methods("GET")
+
+	webhooks := r.PathPrefix("/webhooks").Subrouter()
+	webhooks.Use(WebhookAuthMiddleware(manager, h.webhooks, adminToken))
+	webhooks.HandleFunc("/{webhookId}", h.DeleteWebhook).Methods("DELETE")
+	webhooks.HandleFunc("/{id}/deliveries", h.GetWebhookDeliveries).Methods("GET")
+
+	messages := r.PathPrefix("/messages").Subrouter()
+	messages.Use(auth)
+	messages.HandleFunc("/text", h.SendTextMessage).Methods("POST")
+	messages.HandleFunc("/media", h.SendMediaMessage).Methods("POST")
+	messages.HandleFunc("/presence", h.SendPresence).Methods("POST")
+	messages.HandleFunc("/location", h.SendLocationMessage).Methods("POST")
+	messages.HandleFunc("/contact", h.SendContactMessage).Methods("POST")
+	messages.HandleFunc("/buttons", h.SendButtonsMessage).Methods("POST")
+	messages.HandleFunc("/list", h.SendListMessage).Methods("POST")
+	messages.HandleFunc("/reply", h.SendReplyMessage).Methods("POST")
+
+	media := r.PathPrefix("/media").Subrouter()
+	media.Use(auth)
+	media.HandleFunc("/{instanceId}/{messageId}", h.GetMedia).Methods("GET")
+
+	r.Handle("/ws/{instanceId}", auth(http.HandlerFunc(h.WebSocketHandler)))
+
+	return r
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds process-wide settings loaded from the environment
+type Config struct {
+	Port             string
+	DBPath           string
+	MediaPath        string
+	WebhookStatePath string
+	HistoryDBPath    string
+
+	// MediaMaxSizeBytes caps how large an incoming attachment the auto-downloader will accept
+	MediaMaxSizeBytes int64
+	// MediaTTL is how long downloaded media is kept on disk before cleanup removes it
+	MediaTTL time.Duration
+	// MediaDownloadViewOnce enables auto-download of view-once media, which is skipped by default
+	MediaDownloadViewOnce bool
+
+	// AdminToken is a bearer token accepted for every instance, in addition to each
+	// instance's own API key. Leave empty to disable admin-token auth.
+	AdminToken string
+	// AllowedOrigins lists the Origin headers the WebSocket upgrader accepts; empty allows all
+	AllowedOrigins []string
+}
+
+// Load reads configuration from environment variables, falling back to sane defaults
+func Load() *Config {
+	return &Config{
+		Port:                  getEnv("PORT", "8080"),
+		DBPath:                getEnv("DB_PATH", "./data/whatsmeow.db"),
+		MediaPath:             getEnv("MEDIA_PATH", "./media"),
+		WebhookStatePath:      getEnv("WEBHOOK_STATE_PATH", "./data/webhooks.json"),
+		HistoryDBPath:         getEnv("HISTORY_DB_PATH", "./data/history.db"),
+		MediaMaxSizeBytes:     getEnvInt64("MEDIA_MAX_SIZE_BYTES", 50*1024*1024),
+		MediaTTL:              getEnvDuration("MEDIA_TTL", 7*24*time.Hour),
+		MediaDownloadViewOnce: getEnvBool("MEDIA_DOWNLOAD_VIEW_ONCE", false),
+		AdminToken:            getEnv("ADMIN_TOKEN", ""),
+		AllowedOrigins:        getEnvList("ALLOWED_ORIGINS"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+func getEnvList(key string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	parts := strings.Split(v, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,204 @@
+// Package history persists WhatsApp chat history as it streams in from
+// whatsmeow's post-pairing history sync, and serves it back out paginated.
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Chat is a conversation thread, as last seen during history sync ingestion
+type Chat struct {
+	JID             string `json:"jid"`
+	Name            string `json:"name,omitempty"`
+	LastMessageTime int64  `json:"lastMessageTime"`
+}
+
+// Message is one stored chat message, as ingested from history sync
+type Message struct {
+	ID        string `json:"id"`
+	ChatJID   string `json:"chatJid"`
+	Sender    string `json:"sender"`
+	Text      string `json:"text,omitempty"`
+	FromMe    bool   `json:"fromMe"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// Store persists per-instance chat/message/contact history to a shared SQLite database
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore opens (and migrates) the history database at dbPath
+func NewStore(dbPath string) (*Store, error) {
+	if err := ensureDir(dbPath); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on", dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func ensureDir(dbPath string) error {
+	return os.MkdirAll(filepath.Dir(dbPath), 0o755)
+}
+
+func migrate(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS chats (
+			instance_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			name TEXT,
+			last_message_time INTEGER,
+			PRIMARY KEY (instance_id, jid)
+		)`,
+		`CREATE TABLE IF NOT EXISTS messages (
+			instance_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			sender TEXT,
+			text TEXT,
+			from_me INTEGER,
+			timestamp INTEGER,
+			PRIMARY KEY (instance_id, chat_jid, message_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_messages_chat_time ON messages (instance_id, chat_jid, timestamp)`,
+		`CREATE TABLE IF NOT EXISTS contacts (
+			instance_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			push_name TEXT,
+			PRIMARY KEY (instance_id, jid)
+		)`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to migrate history database: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpsertChat records or updates a chat's display name and most recent message time.
+// A chunk with no name (the history dump arrives in several chunks, and only some
+// carry the conversation's name) never blanks out a name recorded by an earlier chunk.
+func (s *Store) UpsertChat(instanceID string, chat Chat) error {
+	_, err := s.db.Exec(
+		`INSERT INTO chats (instance_id, jid, name, last_message_time) VALUES (?, ?, ?, ?)
+		 ON CONFLICT (instance_id, jid) DO UPDATE SET
+			name = COALESCE(NULLIF(excluded.name, ''), chats.name),
+			last_message_time = MAX(chats.last_message_time, excluded.last_message_time)`,
+		instanceID, chat.JID, chat.Name, chat.LastMessageTime,
+	)
+	return err
+}
+
+// InsertMessage stores one history-synced message, ignoring duplicates
+func (s *Store) InsertMessage(instanceID string, msg Message) error {
+	_, err := s.db.Exec(
+		`INSERT OR IGNORE INTO messages (instance_id, chat_jid, message_id, sender, text, from_me, timestamp)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		instanceID, msg.ChatJID, msg.ID, msg.Sender, msg.Text, msg.FromMe, msg.Timestamp,
+	)
+	return err
+}
+
+// UpsertContactPushName records the push name whatsmeow observed for jid
+func (s *Store) UpsertContactPushName(instanceID, jid, pushName string) error {
+	if pushName == "" {
+		return nil
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO contacts (instance_id, jid, push_name) VALUES (?, ?, ?)
+		 ON CONFLICT (instance_id, jid) DO UPDATE SET push_name = excluded.push_name`,
+		instanceID, jid, pushName,
+	)
+	return err
+}
+
+// ListChats returns up to limit chats for instanceID ordered by most recent activity,
+// starting after cursor (a chat JID from a previous page's last row; empty for the first page)
+func (s *Store) ListChats(instanceID string, limit int, cursor string) ([]Chat, error) {
+	var rows *sql.Rows
+	var err error
+	if cursor == "" {
+		rows, err = s.db.Query(
+			`SELECT jid, name, last_message_time FROM chats WHERE instance_id = ?
+			 ORDER BY last_message_time DESC, jid DESC LIMIT ?`,
+			instanceID, limit,
+		)
+	} else {
+		rows, err = s.db.Query(
+			`SELECT jid, name, last_message_time FROM chats WHERE instance_id = ?
+			 AND (last_message_time, jid) < (SELECT last_message_time, jid FROM chats WHERE instance_id = ? AND jid = ?)
+			 ORDER BY last_message_time DESC, jid DESC LIMIT ?`,
+			instanceID, instanceID, cursor, limit,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []Chat
+	for rows.Next() {
+		var c Chat
+		var name sql.NullString
+		if err := rows.Scan(&c.JID, &name, &c.LastMessageTime); err != nil {
+			return nil, err
+		}
+		c.Name = name.String
+		chats = append(chats, c)
+	}
+	return chats, rows.Err()
+}
+
+// ListMessages returns up to limit messages in chatJID older than before (a unix timestamp;
+// 0 means start from the most recent), newest first
+func (s *Store) ListMessages(instanceID, chatJID string, before int64, limit int) ([]Message, error) {
+	var rows *sql.Rows
+	var err error
+	if before <= 0 {
+		rows, err = s.db.Query(
+			`SELECT message_id, sender, text, from_me, timestamp FROM messages
+			 WHERE instance_id = ? AND chat_jid = ? ORDER BY timestamp DESC LIMIT ?`,
+			instanceID, chatJID, limit,
+		)
+	} else {
+		rows, err = s.db.Query(
+			`SELECT message_id, sender, text, from_me, timestamp FROM messages
+			 WHERE instance_id = ? AND chat_jid = ? AND timestamp < ?
+			 ORDER BY timestamp DESC LIMIT ?`,
+			instanceID, chatJID, before, limit,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var m Message
+		var text sql.NullString
+		if err := rows.Scan(&m.ID, &m.Sender, &text, &m.FromMe, &m.Timestamp); err != nil {
+			return nil, err
+		}
+		m.ChatJID = chatJID
+		m.Text = text.String
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
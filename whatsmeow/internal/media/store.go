@@ -0,0 +1,114 @@
+// Package media stores downloaded WhatsApp attachments on disk and serves
+// them back out over HTTP, with a size cap on ingest and TTL-based cleanup.
+package media
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Store persists media files under rootDir/{instanceID}/{messageID}.{ext}
+type Store struct {
+	rootDir string
+	maxSize int64
+	ttl     time.Duration
+}
+
+// NewStore creates the store, ensuring rootDir exists
+func NewStore(rootDir string, maxSize int64, ttl time.Duration) (*Store, error) {
+	if err := os.MkdirAll(rootDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	return &Store{rootDir: rootDir, maxSize: maxSize, ttl: ttl}, nil
+}
+
+// MaxSize is the largest attachment, in bytes, the store will accept
+func (s *Store) MaxSize() int64 {
+	return s.maxSize
+}
+
+// isSafePathComponent rejects anything that isn't a single plain path segment, so a
+// WhatsApp-supplied value (e.g. the attacker-controlled stanza ID on an incoming media
+// message) can never be used to escape rootDir via "/", "\", or ".."
+func isSafePathComponent(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, "/\\")
+}
+
+// Save writes data to rootDir/{instanceID}/{messageID}.{ext}, rejecting oversized attachments
+func (s *Store) Save(instanceID, messageID, ext string, data []byte) (string, error) {
+	if !isSafePathComponent(instanceID) || !isSafePathComponent(messageID) {
+		return "", fmt.Errorf("invalid instance or message id")
+	}
+
+	if s.maxSize > 0 && int64(len(data)) > s.maxSize {
+		return "", fmt.Errorf("media is %d bytes, exceeds max of %d", len(data), s.maxSize)
+	}
+
+	dir := filepath.Join(s.rootDir, instanceID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, messageID+"."+ext)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Find locates the stored file for messageID regardless of its extension
+func (s *Store) Find(instanceID, messageID string) (string, error) {
+	if !isSafePathComponent(instanceID) || !isSafePathComponent(messageID) {
+		return "", os.ErrNotExist
+	}
+
+	matches, err := filepath.Glob(filepath.Join(s.rootDir, instanceID, messageID+".*"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", os.ErrNotExist
+	}
+	return matches[0], nil
+}
+
+// StartCleanup periodically removes files older than the store's TTL, until stop is closed
+func (s *Store) StartCleanup(stop <-chan struct{}) {
+	if s.ttl <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sweep()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (s *Store) sweep() {
+	cutoff := time.Now().Add(-s.ttl)
+
+	_ = filepath.Walk(s.rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if info.ModTime().Before(cutoff) {
+			if err := os.Remove(path); err != nil {
+				log.Warn().Err(err).Str("path", path).Msg("Failed to remove expired media file")
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// state is the on-disk snapshot of subscriptions and queued deliveries so a restart
+// doesn't drop events that were still waiting to be retried
+type state struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	Pending       []job          `json:"pending"`
+}
+
+// Store persists webhook state to a JSON file on disk
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newStore(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return &Store{path: path}, nil
+}
+
+func (s *Store) load() (state, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return state{}, nil
+	}
+	if err != nil {
+		return state{}, err
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return state{}, err
+	}
+	return st, nil
+}
+
+func (s *Store) save(subscriptions map[string]*Subscription, pending []job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := state{
+		Subscriptions: make([]Subscription, 0, len(subscriptions)),
+		Pending:       pending,
+	}
+	for _, sub := range subscriptions {
+		st.Subscriptions = append(st.Subscriptions, *sub)
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
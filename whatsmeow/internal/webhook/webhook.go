@@ -0,0 +1,401 @@
+// Package webhook delivers instance events to registered HTTPS URLs, in
+// parallel with the existing WebSocket fanout, with signed payloads and
+// durable retry on failure.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// backoffSchedule is the delay before each retry attempt, indexed by attempt number (0-based)
+var backoffSchedule = []time.Duration{
+	1 * time.Second,
+	5 * time.Second,
+	30 * time.Second,
+	5 * time.Minute,
+	30 * time.Minute,
+}
+
+const maxAttempts = 6
+
+// Subscription is one registered webhook URL for an instance. Events filters by
+// broadcast event type (message, qr, pairingCode, pairSuccess, connected, disconnected,
+// loggedOut, syncProgress); empty matches every type. No status, presence, receipt or
+// group.update events are ever dispatched.
+type Subscription struct {
+	ID         string   `json:"id"`
+	InstanceID string   `json:"instanceId"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	Events     []string `json:"events"`
+}
+
+func (s *Subscription) wants(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery records the outcome of one attempt to deliver an event to a subscription
+type Delivery struct {
+	ID             string    `json:"id"`
+	SubscriptionID string    `json:"subscriptionId"`
+	EventType      string    `json:"eventType"`
+	Attempt        int       `json:"attempt"`
+	StatusCode     int       `json:"statusCode,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	Delivered      bool      `json:"delivered"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// job is a queued delivery attempt; it is persisted as-is so retries survive a restart
+type job struct {
+	Sub       Subscription `json:"sub"`
+	EventType string       `json:"eventType"`
+	Body      []byte       `json:"body"`
+	Attempt   int          `json:"attempt"`
+}
+
+// Dispatcher fans events out to every matching subscription through a bounded,
+// per-URL worker queue. Queued and retry-pending deliveries are persisted to disk; the
+// delivery attempt history returned by Deliveries is kept in memory only.
+type Dispatcher struct {
+	httpClient *http.Client
+	store      *Store
+
+	mu            sync.RWMutex
+	subscriptions map[string]*Subscription // by subscription ID
+	queues        map[string]chan job      // by subscription ID
+	done          map[string]chan struct{} // by subscription ID, closed when the worker should stop
+	pending       map[string][]job         // by subscription ID, mirrors queues for persistence
+	deliveries    map[string][]Delivery    // by subscription ID
+}
+
+// NewDispatcher loads any persisted subscriptions/pending deliveries from statePath and
+// starts a worker goroutine per subscription so restarts don't drop queued events
+func NewDispatcher(statePath string) (*Dispatcher, error) {
+	store, err := newStore(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open webhook state: %w", err)
+	}
+
+	d := &Dispatcher{
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		store:         store,
+		subscriptions: make(map[string]*Subscription),
+		queues:        make(map[string]chan job),
+		done:          make(map[string]chan struct{}),
+		pending:       make(map[string][]job),
+		deliveries:    make(map[string][]Delivery),
+	}
+
+	state, err := store.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range state.Subscriptions {
+		sub := sub
+		d.subscriptions[sub.ID] = &sub
+		d.startWorker(sub.ID)
+	}
+	for _, pending := range state.Pending {
+		if pending.Attempt > 0 {
+			d.scheduleRetry(pending.Sub.ID, pending)
+		} else {
+			d.enqueue(pending.Sub.ID, pending)
+		}
+	}
+
+	return d, nil
+}
+
+// Register adds a new webhook subscription for instanceID and starts its worker
+func (d *Dispatcher) Register(instanceID, url, secret string, events []string) *Subscription {
+	sub := &Subscription{
+		ID:         uuid.NewString(),
+		InstanceID: instanceID,
+		URL:        url,
+		Secret:     secret,
+		Events:     events,
+	}
+
+	d.mu.Lock()
+	d.subscriptions[sub.ID] = sub
+	d.mu.Unlock()
+
+	d.startWorker(sub.ID)
+	d.persist()
+	return sub
+}
+
+// Get returns the subscription registered as subscriptionID, so callers can resolve
+// which instance owns it (e.g. to authenticate or authorize a request against it)
+func (d *Dispatcher) Get(subscriptionID string) (Subscription, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	sub, ok := d.subscriptions[subscriptionID]
+	if !ok {
+		return Subscription{}, false
+	}
+	return *sub, true
+}
+
+// List returns every subscription registered for instanceID
+func (d *Dispatcher) List(instanceID string) []Subscription {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var out []Subscription
+	for _, sub := range d.subscriptions {
+		if sub.InstanceID == instanceID {
+			out = append(out, *sub)
+		}
+	}
+	return out
+}
+
+// Remove deletes subscriptionID, stopping further delivery attempts for it
+func (d *Dispatcher) Remove(subscriptionID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.subscriptions[subscriptionID]; !ok {
+		return fmt.Errorf("webhook %s not found", subscriptionID)
+	}
+	delete(d.subscriptions, subscriptionID)
+	if done, ok := d.done[subscriptionID]; ok {
+		close(done)
+		delete(d.done, subscriptionID)
+	}
+	delete(d.queues, subscriptionID)
+	delete(d.pending, subscriptionID)
+	delete(d.deliveries, subscriptionID)
+
+	d.persistLocked()
+	return nil
+}
+
+// Deliveries returns the recorded delivery attempts for subscriptionID, most recent last
+func (d *Dispatcher) Deliveries(subscriptionID string) []Delivery {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]Delivery(nil), d.deliveries[subscriptionID]...)
+}
+
+// Dispatch pushes an event to every subscription for instanceID whose event filter matches
+func (d *Dispatcher) Dispatch(instanceID, eventType string, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal webhook payload")
+		return
+	}
+
+	d.mu.RLock()
+	ids := make([]string, 0, len(d.subscriptions))
+	for id, sub := range d.subscriptions {
+		if sub.InstanceID == instanceID && sub.wants(eventType) {
+			ids = append(ids, id)
+		}
+	}
+	d.mu.RUnlock()
+
+	for _, id := range ids {
+		d.mu.RLock()
+		sub := *d.subscriptions[id]
+		d.mu.RUnlock()
+		d.enqueue(id, job{Sub: sub, EventType: eventType, Body: body})
+	}
+}
+
+// enqueue pushes j onto subscriptionID's worker queue and mirrors it in `pending`
+// so the dispatcher can persist in-flight/retrying deliveries across restarts
+func (d *Dispatcher) enqueue(subscriptionID string, j job) {
+	d.mu.Lock()
+	q, ok := d.queues[subscriptionID]
+	done := d.done[subscriptionID]
+	if !ok {
+		d.mu.Unlock()
+		return
+	}
+	d.pending[subscriptionID] = append(d.pending[subscriptionID], j)
+	d.mu.Unlock()
+
+	// q is never closed (only done is, on Remove), so this send can never panic even if
+	// Remove races with us between the lookup above and the select below.
+	select {
+	case q <- j:
+	case <-done:
+		d.dequeue(subscriptionID, j)
+	default:
+		log.Warn().Str("webhookId", subscriptionID).Msg("Webhook queue full, dropping event")
+		d.dequeue(subscriptionID, j)
+	}
+}
+
+// scheduleRetry marks j as pending and re-enqueues it onto subscriptionID's queue after
+// its backoff delay, via a timer rather than blocking the worker goroutine so one job's
+// retry wait never stalls delivery of other queued events for the same subscription
+func (d *Dispatcher) scheduleRetry(subscriptionID string, j job) {
+	idx := j.Attempt - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(backoffSchedule) {
+		idx = len(backoffSchedule) - 1
+	}
+
+	d.mu.Lock()
+	d.pending[subscriptionID] = append(d.pending[subscriptionID], j)
+	d.mu.Unlock()
+
+	time.AfterFunc(backoffSchedule[idx], func() {
+		d.mu.RLock()
+		q, ok := d.queues[subscriptionID]
+		done := d.done[subscriptionID]
+		d.mu.RUnlock()
+		if !ok {
+			return
+		}
+
+		select {
+		case q <- j:
+		case <-done:
+			d.dequeue(subscriptionID, j)
+		default:
+			log.Warn().Str("webhookId", subscriptionID).Msg("Webhook queue full, dropping retried event")
+			d.dequeue(subscriptionID, j)
+		}
+	})
+}
+
+// dequeue removes one instance of j from `pending`, called once it's been sent or dropped
+func (d *Dispatcher) dequeue(subscriptionID string, j job) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	jobs := d.pending[subscriptionID]
+	for i, p := range jobs {
+		if p.Attempt == j.Attempt && p.EventType == j.EventType && string(p.Body) == string(j.Body) {
+			d.pending[subscriptionID] = append(jobs[:i], jobs[i+1:]...)
+			break
+		}
+	}
+}
+
+func (d *Dispatcher) startWorker(subscriptionID string) {
+	d.mu.Lock()
+	if _, ok := d.queues[subscriptionID]; ok {
+		d.mu.Unlock()
+		return
+	}
+	q := make(chan job, 256)
+	done := make(chan struct{})
+	d.queues[subscriptionID] = q
+	d.done[subscriptionID] = done
+	d.mu.Unlock()
+
+	go d.worker(subscriptionID, q, done)
+}
+
+func (d *Dispatcher) worker(subscriptionID string, q chan job, done chan struct{}) {
+	for {
+		select {
+		case j := <-q:
+			statusCode, err := d.deliver(j)
+			delivered := err == nil && statusCode >= 200 && statusCode < 300
+
+			d.recordDelivery(subscriptionID, j, statusCode, err, delivered)
+			d.dequeue(subscriptionID, j)
+
+			if !delivered && j.Attempt+1 < maxAttempts {
+				j.Attempt++
+				d.scheduleRetry(subscriptionID, j)
+			}
+			d.persist()
+		case <-done:
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(j job) (int, error) {
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	signature := sign(j.Sub.Secret, timestamp, j.Body)
+
+	req, err := http.NewRequest(http.MethodPost, j.Sub.URL, bytes.NewReader(j.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Instance-ID", j.Sub.InstanceID)
+	req.Header.Set("X-Event-Type", j.EventType)
+	req.Header.Set("X-Timestamp", timestamp)
+	req.Header.Set("X-Signature", "sha256="+signature)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) recordDelivery(subscriptionID string, j job, statusCode int, err error, delivered bool) {
+	del := Delivery{
+		ID:             uuid.NewString(),
+		SubscriptionID: subscriptionID,
+		EventType:      j.EventType,
+		Attempt:        j.Attempt + 1,
+		StatusCode:     statusCode,
+		Delivered:      delivered,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err != nil {
+		del.Error = err.Error()
+	}
+
+	d.mu.Lock()
+	d.deliveries[subscriptionID] = append(d.deliveries[subscriptionID], del)
+	d.mu.Unlock()
+}
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Dispatcher) persist() {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	d.persistLocked()
+}
+
+func (d *Dispatcher) persistLocked() {
+	var pending []job
+	for _, jobs := range d.pending {
+		pending = append(pending, jobs...)
+	}
+	if err := d.store.save(d.subscriptions, pending); err != nil {
+		log.Error().Err(err).Msg("Failed to persist webhook state")
+	}
+}
@@ -0,0 +1,47 @@
+package whatsapp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+)
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ValidateAPIKey reports whether token is the current API key for instanceID
+func (m *Manager) ValidateAPIKey(instanceID, token string) bool {
+	inst, ok := m.getInstance(instanceID)
+	if !ok {
+		return false
+	}
+
+	inst.RLock()
+	defer inst.RUnlock()
+	return inst.APIKey != "" && subtle.ConstantTimeCompare([]byte(inst.APIKey), []byte(token)) == 1
+}
+
+// RotateAPIKey generates and stores a new API key for instanceID, replacing any existing one
+func (m *Manager) RotateAPIKey(instanceID string) (string, error) {
+	inst, err := m.getOrCreateInstance(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		return "", err
+	}
+
+	inst.Lock()
+	inst.APIKey = key
+	inst.Unlock()
+
+	return key, nil
+}
@@ -0,0 +1,84 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Contact is the enriched view of a whatsmeow store contact returned to API clients
+type Contact struct {
+	JID           string `json:"jid"`
+	Name          string `json:"name"`
+	Notify        string `json:"notify"`
+	BusinessName  string `json:"businessName,omitempty"`
+	ProfilePicURL string `json:"profilePicUrl,omitempty"`
+}
+
+// GetContacts returns every contact stored for instanceID, with profile picture URLs attached
+func (m *Manager) GetContacts(instanceID string) ([]Contact, error) {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := inst.Client.Store.Contacts.GetAllContacts(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contacts: %w", err)
+	}
+
+	contacts := make([]Contact, 0, len(stored))
+	for jid, info := range stored {
+		c := Contact{
+			JID:          jid.String(),
+			Name:         info.FullName,
+			Notify:       info.PushName,
+			BusinessName: info.BusinessName,
+		}
+
+		if pic, err := inst.Client.GetProfilePictureInfo(context.Background(), jid, nil); err == nil && pic != nil {
+			c.ProfilePicURL = pic.URL
+		}
+
+		contacts = append(contacts, c)
+	}
+
+	return contacts, nil
+}
+
+// NumberCheckResult reports whether a given phone number has a WhatsApp account
+type NumberCheckResult struct {
+	Query        string `json:"query"`
+	JID          string `json:"jid,omitempty"`
+	IsOnWhatsApp bool   `json:"isOnWhatsApp"`
+	IsBusiness   bool   `json:"isBusiness"`
+	VerifiedName string `json:"verifiedName,omitempty"`
+}
+
+// CheckNumbers batches an IsOnWhatsApp lookup for the given phone numbers
+func (m *Manager) CheckNumbers(instanceID string, numbers []string) ([]NumberCheckResult, error) {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := inst.Client.IsOnWhatsApp(context.Background(), numbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check numbers: %w", err)
+	}
+
+	out := make([]NumberCheckResult, 0, len(results))
+	for _, r := range results {
+		entry := NumberCheckResult{
+			Query:        r.Query,
+			JID:          r.JID.String(),
+			IsOnWhatsApp: r.IsIn,
+		}
+		if r.VerifiedName != nil {
+			entry.IsBusiness = true
+			entry.VerifiedName = r.VerifiedName.Details.GetVerifiedName()
+		}
+		out = append(out, entry)
+	}
+
+	return out, nil
+}
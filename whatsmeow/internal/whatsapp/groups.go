@@ -0,0 +1,65 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+)
+
+// GroupParticipant describes one member of a group and their admin status
+type GroupParticipant struct {
+	JID      string `json:"jid"`
+	IsAdmin  bool   `json:"isAdmin"`
+	IsSuper  bool   `json:"isSuperAdmin"`
+	JoinedAt int64  `json:"joinedAt,omitempty"`
+}
+
+// Group is the enriched view of a whatsmeow joined group returned to API clients
+type Group struct {
+	JID          string             `json:"jid"`
+	Subject      string             `json:"subject"`
+	Description  string             `json:"description,omitempty"`
+	Owner        string             `json:"owner,omitempty"`
+	Participants []GroupParticipant `json:"participants"`
+	InviteLink   string             `json:"inviteLink,omitempty"`
+}
+
+// GetGroups returns every group instanceID has joined, with participants and invite link attached
+func (m *Manager) GetGroups(instanceID string) ([]Group, error) {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	joined, err := inst.Client.GetJoinedGroups(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load joined groups: %w", err)
+	}
+
+	groups := make([]Group, 0, len(joined))
+	for _, g := range joined {
+		group := Group{
+			JID:         g.JID.String(),
+			Subject:     g.Name,
+			Description: g.Topic,
+		}
+		if !g.OwnerJID.IsEmpty() {
+			group.Owner = g.OwnerJID.String()
+		}
+
+		for _, p := range g.Participants {
+			group.Participants = append(group.Participants, GroupParticipant{
+				JID:     p.JID.String(),
+				IsAdmin: p.IsAdmin,
+				IsSuper: p.IsSuperAdmin,
+			})
+		}
+
+		if link, err := inst.Client.GetGroupInviteLink(context.Background(), g.JID, false); err == nil {
+			group.InviteLink = link
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,85 @@
+package whatsapp
+
+import (
+	"github.com/rs/zerolog/log"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"whatsmeow-service/internal/history"
+)
+
+// handleHistorySync persists one chunk of the post-pairing history dump and reports
+// ingestion progress to WebSocket subscribers, since the dump can arrive in several chunks
+func (m *Manager) handleHistorySync(inst *Instance, evt *events.HistorySync) {
+	if m.history == nil {
+		return
+	}
+
+	data := evt.Data
+	conversations := data.GetConversations()
+
+	for _, conv := range conversations {
+		chatJID := conv.GetID()
+
+		var lastTimestamp int64
+		for _, hsMsg := range conv.GetMessages() {
+			webMsg := hsMsg.GetMessage()
+			if webMsg == nil {
+				continue
+			}
+
+			info := webMsg.GetKey()
+			timestamp := int64(webMsg.GetMessageTimestamp())
+			if timestamp > lastTimestamp {
+				lastTimestamp = timestamp
+			}
+
+			msg := history.Message{
+				ID:        info.GetID(),
+				ChatJID:   chatJID,
+				Sender:    info.GetParticipant(),
+				FromMe:    info.GetFromMe(),
+				Timestamp: timestamp,
+			}
+			if text := webMsg.GetMessage().GetConversation(); text != "" {
+				msg.Text = text
+			} else if ext := webMsg.GetMessage().GetExtendedTextMessage(); ext != nil {
+				msg.Text = ext.GetText()
+			}
+
+			if err := m.history.InsertMessage(inst.ID, msg); err != nil {
+				log.Error().Err(err).Str("instanceId", inst.ID).Msg("Failed to store history-synced message")
+			}
+
+			if pushName := hsMsg.GetMessage().GetPushName(); pushName != "" {
+				_ = m.history.UpsertContactPushName(inst.ID, info.GetParticipant(), pushName)
+			}
+		}
+
+		if err := m.history.UpsertChat(inst.ID, history.Chat{
+			JID:             chatJID,
+			Name:            conv.GetName(),
+			LastMessageTime: lastTimestamp,
+		}); err != nil {
+			log.Error().Err(err).Str("instanceId", inst.ID).Msg("Failed to store history-synced chat")
+		}
+	}
+
+	progressPercent := int(data.GetProgress())
+
+	inst.Lock()
+	inst.historySyncProcessed += len(conversations)
+	processed := inst.historySyncProcessed
+	if progressPercent >= 100 {
+		inst.historySyncProcessed = 0
+	}
+	inst.Unlock()
+
+	// whatsmeow's HistorySync proto never reports a total conversation count, only a
+	// 0-100 completion percentage, so "total" is named for what it actually is instead
+	// of implying an item count clients could compute real progress from.
+	m.broadcast(inst, "syncProgress", map[string]interface{}{
+		"processed":       processed,
+		"progressPercent": progressPercent,
+		"phase":           data.GetSyncType().String(),
+	})
+}
@@ -0,0 +1,108 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// downloadableMedia describes the subset of a waE2E media message the manager needs
+// to fetch and store it, regardless of which concrete message type it came from
+type downloadableMedia struct {
+	whatsmeow.DownloadableMessage
+	mimetype   string
+	fileLength uint64 // advertised size, from the message proto, before any bytes are fetched
+}
+
+// extractDownloadable returns the media payload of evt, if it carries one
+func extractDownloadable(evt *events.Message) *downloadableMedia {
+	msg := evt.Message
+	switch {
+	case msg.GetImageMessage() != nil:
+		img := msg.GetImageMessage()
+		return &downloadableMedia{img, img.GetMimetype(), img.GetFileLength()}
+	case msg.GetVideoMessage() != nil:
+		vid := msg.GetVideoMessage()
+		return &downloadableMedia{vid, vid.GetMimetype(), vid.GetFileLength()}
+	case msg.GetAudioMessage() != nil:
+		aud := msg.GetAudioMessage()
+		return &downloadableMedia{aud, aud.GetMimetype(), aud.GetFileLength()}
+	case msg.GetDocumentMessage() != nil:
+		doc := msg.GetDocumentMessage()
+		return &downloadableMedia{doc, doc.GetMimetype(), doc.GetFileLength()}
+	case msg.GetStickerMessage() != nil:
+		sticker := msg.GetStickerMessage()
+		return &downloadableMedia{sticker, sticker.GetMimetype(), sticker.GetFileLength()}
+	default:
+		return nil
+	}
+}
+
+// handleIncomingMessage downloads and stores any attached media, then broadcasts the
+// event (with a mediaUrl, if applicable) to WebSocket subscribers and webhooks
+func (m *Manager) handleIncomingMessage(inst *Instance, evt *events.Message) {
+	data := map[string]interface{}{
+		"messageId": evt.Info.ID,
+		"from":      evt.Info.Sender.String(),
+		"chat":      evt.Info.Chat.String(),
+		"timestamp": evt.Info.Timestamp.Unix(),
+		"fromMe":    evt.Info.IsFromMe,
+	}
+
+	if text := evt.Message.GetConversation(); text != "" {
+		data["text"] = text
+	} else if ext := evt.Message.GetExtendedTextMessage(); ext != nil {
+		data["text"] = ext.GetText()
+	}
+
+	if dl := extractDownloadable(evt); dl != nil {
+		if evt.IsViewOnce && !m.downloadViewOnce {
+			log.Info().Str("instanceId", inst.ID).Str("messageId", evt.Info.ID).
+				Msg("Skipping auto-download of view-once media")
+		} else if mediaURL, err := m.downloadAndStore(inst, evt.Info.ID, dl); err != nil {
+			log.Error().Err(err).Str("instanceId", inst.ID).Str("messageId", evt.Info.ID).
+				Msg("Failed to download incoming media")
+		} else {
+			data["mediaUrl"] = mediaURL
+			data["mimeType"] = dl.mimetype
+		}
+	}
+
+	m.broadcast(inst, "message", data)
+}
+
+func (m *Manager) downloadAndStore(inst *Instance, messageID string, dl *downloadableMedia) (string, error) {
+	if m.media == nil {
+		return "", fmt.Errorf("no media store configured")
+	}
+
+	if max := m.media.MaxSize(); max > 0 && dl.fileLength > uint64(max) {
+		return "", fmt.Errorf("media is %d bytes, exceeds max of %d", dl.fileLength, max)
+	}
+
+	content, err := inst.Client.Download(context.Background(), dl.DownloadableMessage)
+	if err != nil {
+		return "", fmt.Errorf("failed to download media: %w", err)
+	}
+
+	ext := extensionForMimetype(dl.mimetype)
+	if _, err := m.media.Save(inst.ID, messageID, ext, content); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/media/%s/%s", inst.ID, messageID), nil
+}
+
+func extensionForMimetype(mimetype string) string {
+	base, _, _ := strings.Cut(mimetype, ";")
+	exts, err := mime.ExtensionsByType(base)
+	if err != nil || len(exts) == 0 {
+		return "bin"
+	}
+	return strings.TrimPrefix(exts[0], ".")
+}
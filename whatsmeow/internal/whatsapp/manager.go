@@ -0,0 +1,352 @@
+// Package whatsapp wraps go.mau.fi/whatsmeow to manage multiple logged-in
+// WhatsApp instances and fan out their events to HTTP/WebSocket clients.
+package whatsapp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/skip2/go-qrcode"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"whatsmeow-service/internal/history"
+	"whatsmeow-service/internal/media"
+	"whatsmeow-service/internal/webhook"
+)
+
+// Instance represents a single WhatsApp session managed by the Manager
+type Instance struct {
+	sync.RWMutex
+
+	ID           string
+	Client       *whatsmeow.Client
+	Status       string // "disconnected", "qr", "pairing", "connected"
+	QRCodeBase64 string
+	PairingCode  string
+	WANumber     string
+	WAName       string
+	APIKey       string
+
+	historySyncProcessed int // running count of conversations ingested across the current history sync
+
+	subMu       sync.Mutex
+	subscribers map[chan map[string]interface{}]struct{}
+}
+
+// Manager owns every Instance and the underlying whatsmeow device store
+type Manager struct {
+	mu        sync.RWMutex
+	instances map[string]*Instance
+	container *sqlstore.Container
+	webhooks  *webhook.Dispatcher
+
+	media            *media.Store
+	downloadViewOnce bool
+	history          *history.Store
+}
+
+// SetHistoryStore wires a history.Store so post-pairing history sync chunks get persisted
+func (m *Manager) SetHistoryStore(store *history.Store) {
+	m.history = store
+}
+
+// SetWebhookDispatcher wires a webhook.Dispatcher so every broadcast event is
+// also delivered to each instance's registered webhook URLs
+func (m *Manager) SetWebhookDispatcher(d *webhook.Dispatcher) {
+	m.webhooks = d
+}
+
+// SetMediaStore wires a media.Store so incoming attachments are auto-downloaded.
+// downloadViewOnce controls whether view-once media is downloaded as well.
+func (m *Manager) SetMediaStore(store *media.Store, downloadViewOnce bool) {
+	m.media = store
+	m.downloadViewOnce = downloadViewOnce
+}
+
+// NewManager opens the shared SQLite device store and returns a ready Manager
+func NewManager(dbPath string) (*Manager, error) {
+	container, err := sqlstore.New(context.Background(), "sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on", dbPath), waLog.Noop)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open device store: %w", err)
+	}
+
+	return &Manager{
+		instances: make(map[string]*Instance),
+		container: container,
+	}, nil
+}
+
+func (m *Manager) getOrCreateInstance(instanceID string) (*Instance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if inst, ok := m.instances[instanceID]; ok {
+		return inst, nil
+	}
+
+	deviceStore := m.container.NewDevice()
+	client := whatsmeow.NewClient(deviceStore, waLog.Noop)
+
+	inst := &Instance{
+		ID:          instanceID,
+		Client:      client,
+		Status:      "disconnected",
+		subscribers: make(map[chan map[string]interface{}]struct{}),
+	}
+	client.AddEventHandler(m.eventHandler(inst))
+
+	m.instances[instanceID] = inst
+	return inst, nil
+}
+
+func (m *Manager) getInstance(instanceID string) (*Instance, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	inst, ok := m.instances[instanceID]
+	return inst, ok
+}
+
+// Connect starts (or resumes) a WhatsApp session for instanceID, emitting a
+// QR code over the WebSocket event bus when the device isn't paired yet
+func (m *Manager) Connect(instanceID string) (*Instance, error) {
+	inst, err := m.getOrCreateInstance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	inst.RLock()
+	alreadyConnected := inst.Client.IsConnected()
+	inst.RUnlock()
+	if alreadyConnected {
+		return inst, nil
+	}
+
+	if inst.Client.Store.ID == nil {
+		qrChan, _ := inst.Client.GetQRChannel(context.Background())
+		if err := inst.Client.Connect(); err != nil {
+			return nil, fmt.Errorf("failed to connect: %w", err)
+		}
+		go m.watchQRChannel(inst, qrChan)
+	} else {
+		if err := inst.Client.Connect(); err != nil {
+			return nil, fmt.Errorf("failed to connect: %w", err)
+		}
+	}
+
+	return inst, nil
+}
+
+func (m *Manager) watchQRChannel(inst *Instance, qrChan <-chan whatsmeow.QRChannelItem) {
+	for evt := range qrChan {
+		switch evt.Event {
+		case "code":
+			png, err := qrcode.Encode(evt.Code, qrcode.Medium, 256)
+			if err != nil {
+				log.Error().Err(err).Str("instanceId", inst.ID).Msg("Failed to render QR code")
+				continue
+			}
+
+			inst.Lock()
+			inst.Status = "qr"
+			inst.QRCodeBase64 = base64.StdEncoding.EncodeToString(png)
+			inst.Unlock()
+
+			m.broadcast(inst, "qr", map[string]interface{}{
+				"qrCode": inst.QRCodeBase64,
+			})
+		case "success":
+			inst.Lock()
+			inst.Status = "connected"
+			inst.QRCodeBase64 = ""
+			inst.Unlock()
+		case "timeout":
+			inst.Lock()
+			inst.Status = "disconnected"
+			inst.Unlock()
+		}
+	}
+}
+
+// Disconnect tears down the live connection but keeps the paired device
+func (m *Manager) Disconnect(instanceID string) error {
+	inst, ok := m.getInstance(instanceID)
+	if !ok {
+		return fmt.Errorf("instance %s not found", instanceID)
+	}
+
+	inst.Client.Disconnect()
+
+	inst.Lock()
+	inst.Status = "disconnected"
+	inst.Unlock()
+
+	return nil
+}
+
+// Logout disconnects and wipes the paired device so a fresh QR/pairing is needed
+func (m *Manager) Logout(instanceID string) error {
+	inst, ok := m.getInstance(instanceID)
+	if !ok {
+		return fmt.Errorf("instance %s not found", instanceID)
+	}
+
+	if err := inst.Client.Logout(context.Background()); err != nil {
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+
+	inst.Lock()
+	inst.Status = "disconnected"
+	inst.QRCodeBase64 = ""
+	inst.WANumber = ""
+	inst.WAName = ""
+	inst.Unlock()
+
+	return nil
+}
+
+// GetStatus returns the instance's connection status and known WhatsApp identity
+func (m *Manager) GetStatus(instanceID string) (string, map[string]string) {
+	inst, ok := m.getInstance(instanceID)
+	if !ok {
+		return "not_found", map[string]string{}
+	}
+
+	inst.RLock()
+	defer inst.RUnlock()
+	return inst.Status, map[string]string{
+		"waNumber": inst.WANumber,
+		"waName":   inst.WAName,
+	}
+}
+
+// GetQRCode returns the most recently generated QR code for instanceID, if any
+func (m *Manager) GetQRCode(instanceID string) (bool, string) {
+	inst, ok := m.getInstance(instanceID)
+	if !ok {
+		return false, ""
+	}
+
+	inst.RLock()
+	defer inst.RUnlock()
+	return inst.QRCodeBase64 != "", inst.QRCodeBase64
+}
+
+// Subscribe registers a channel that receives every event broadcast for instanceID
+func (m *Manager) Subscribe(instanceID string) chan map[string]interface{} {
+	inst, err := m.getOrCreateInstance(instanceID)
+	if err != nil {
+		return nil
+	}
+
+	ch := make(chan map[string]interface{}, 32)
+
+	inst.subMu.Lock()
+	inst.subscribers[ch] = struct{}{}
+	inst.subMu.Unlock()
+
+	return ch
+}
+
+// Unsubscribe removes a channel previously returned by Subscribe
+func (m *Manager) Unsubscribe(instanceID string, ch chan map[string]interface{}) {
+	inst, ok := m.getInstance(instanceID)
+	if !ok {
+		return
+	}
+
+	inst.subMu.Lock()
+	delete(inst.subscribers, ch)
+	inst.subMu.Unlock()
+	close(ch)
+}
+
+func (m *Manager) broadcast(inst *Instance, eventType string, data map[string]interface{}) {
+	event := map[string]interface{}{
+		"type":       eventType,
+		"instanceId": inst.ID,
+		"data":       data,
+	}
+
+	inst.subMu.Lock()
+	for ch := range inst.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Str("instanceId", inst.ID).Msg("Dropping event, subscriber channel full")
+		}
+	}
+	inst.subMu.Unlock()
+
+	if m.webhooks != nil {
+		m.webhooks.Dispatch(inst.ID, eventType, event)
+	}
+}
+
+func (m *Manager) eventHandler(inst *Instance) func(interface{}) {
+	return func(rawEvt interface{}) {
+		switch evt := rawEvt.(type) {
+		case *events.QR:
+			// handled via watchQRChannel instead, nothing to do here
+		case *events.Message:
+			m.handleIncomingMessage(inst, evt)
+		case *events.HistorySync:
+			m.handleHistorySync(inst, evt)
+		case *events.PairSuccess:
+			inst.Lock()
+			inst.Status = "pairing"
+			inst.WANumber = evt.ID.User
+			inst.Unlock()
+
+			m.broadcast(inst, "pairSuccess", map[string]interface{}{
+				"waNumber": evt.ID.User,
+			})
+		case *events.Connected:
+			inst.Lock()
+			inst.Status = "connected"
+			inst.QRCodeBase64 = ""
+			inst.PairingCode = ""
+			if inst.Client.Store.ID != nil {
+				inst.WANumber = inst.Client.Store.ID.User
+			}
+			if inst.Client.Store.PushName != "" {
+				inst.WAName = inst.Client.Store.PushName
+			}
+			inst.Unlock()
+
+			m.broadcast(inst, "connected", map[string]interface{}{
+				"waNumber": inst.WANumber,
+				"waName":   inst.WAName,
+			})
+		case *events.Disconnected:
+			inst.Lock()
+			inst.Status = "disconnected"
+			inst.Unlock()
+
+			m.broadcast(inst, "disconnected", map[string]interface{}{})
+		case *events.LoggedOut:
+			inst.Lock()
+			inst.Status = "disconnected"
+			inst.WANumber = ""
+			inst.WAName = ""
+			inst.Unlock()
+
+			m.broadcast(inst, "loggedOut", map[string]interface{}{
+				"reason": evt.Reason.String(),
+			})
+		}
+	}
+}
+
+// jidFromPhone formats an E.164 phone number as a whatsmeow user JID
+func jidFromPhone(phone string) types.JID {
+	return types.NewJID(phone, types.DefaultUserServer)
+}
@@ -0,0 +1,91 @@
+package whatsapp
+
+import (
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func mediaTypeToMMType(mediaType string) whatsmeow.MediaType {
+	switch mediaType {
+	case "video":
+		return whatsmeow.MediaVideo
+	case "audio":
+		return whatsmeow.MediaAudio
+	case "document":
+		return whatsmeow.MediaDocument
+	default:
+		return whatsmeow.MediaImage
+	}
+}
+
+func buildMediaMessage(mediaType, contentType, caption string, uploaded whatsmeow.UploadResponse) *waE2E.Message {
+	switch mediaType {
+	case "video":
+		return &waE2E.Message{
+			VideoMessage: &waE2E.VideoMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(contentType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+				Caption:       proto.String(caption),
+			},
+		}
+	case "audio":
+		return &waE2E.Message{
+			AudioMessage: &waE2E.AudioMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(contentType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+			},
+		}
+	case "document":
+		return &waE2E.Message{
+			DocumentMessage: &waE2E.DocumentMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(contentType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+				Caption:       proto.String(caption),
+			},
+		}
+	default:
+		return &waE2E.Message{
+			ImageMessage: &waE2E.ImageMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(contentType),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+				Caption:       proto.String(caption),
+			},
+		}
+	}
+}
+
+// attachContextInfo sets ctx on whichever media submessage buildMediaMessage populated,
+// so quoted replies work the same way on media as they do on plain text
+func attachContextInfo(msg *waE2E.Message, ctx *waE2E.ContextInfo) {
+	switch {
+	case msg.VideoMessage != nil:
+		msg.VideoMessage.ContextInfo = ctx
+	case msg.AudioMessage != nil:
+		msg.AudioMessage.ContextInfo = ctx
+	case msg.DocumentMessage != nil:
+		msg.DocumentMessage.ContextInfo = ctx
+	case msg.ImageMessage != nil:
+		msg.ImageMessage.ContextInfo = ctx
+	}
+}
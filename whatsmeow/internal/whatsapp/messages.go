@@ -0,0 +1,119 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func (m *Manager) resolveClient(instanceID string) (*Instance, error) {
+	inst, ok := m.getInstance(instanceID)
+	if !ok {
+		return nil, fmt.Errorf("instance %s not found", instanceID)
+	}
+	if !inst.Client.IsConnected() {
+		return nil, fmt.Errorf("instance %s is not connected", instanceID)
+	}
+	return inst, nil
+}
+
+// SendTextMessage sends a plain text message and returns the outgoing message ID
+func (m *Manager) SendTextMessage(instanceID, to, text string) (string, error) {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	msg := &waE2E.Message{
+		Conversation: proto.String(text),
+	}
+
+	resp, err := inst.Client.SendMessage(context.Background(), jidFromPhone(to), msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send text message: %w", err)
+	}
+
+	return resp.ID, nil
+}
+
+// SendMediaMessage downloads mediaURL, uploads it to WhatsApp, and sends it as mediaType.
+// quoted is optional (nil means this isn't a reply) and works the same as it does for
+// SendReplyMessage, so a reply can quote into an image/video/audio/document send too.
+func (m *Manager) SendMediaMessage(instanceID, to, mediaURL, caption, mediaType string, quoted *QuotedMessage) (string, error) {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	data, contentType, err := downloadMedia(mediaURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download media: %w", err)
+	}
+
+	mmType := mediaTypeToMMType(mediaType)
+	uploaded, err := inst.Client.Upload(context.Background(), data, mmType)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload media: %w", err)
+	}
+
+	msg := buildMediaMessage(mediaType, contentType, caption, uploaded)
+	if quoted != nil {
+		attachContextInfo(msg, buildContextInfo(*quoted, inst.Client.Store.ID.ToNonAD().String()))
+	}
+
+	resp, err := inst.Client.SendMessage(context.Background(), jidFromPhone(to), msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send media message: %w", err)
+	}
+
+	return resp.ID, nil
+}
+
+// SendPresence updates the chat presence (composing/recording/paused) shown to `to`
+func (m *Manager) SendPresence(instanceID, to, presence string) error {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return err
+	}
+
+	var state types.ChatPresence
+	var media types.ChatPresenceMedia
+	switch presence {
+	case "composing":
+		state, media = types.ChatPresenceComposing, types.ChatPresenceMediaText
+	case "recording":
+		state, media = types.ChatPresenceComposing, types.ChatPresenceMediaAudio
+	case "paused":
+		state, media = types.ChatPresencePaused, types.ChatPresenceMediaText
+	default:
+		return fmt.Errorf("unsupported presence %q", presence)
+	}
+
+	return inst.Client.SendChatPresence(context.Background(), jidFromPhone(to), state, media)
+}
+
+func downloadMedia(url string) ([]byte, string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(url))
+	}
+	return data, contentType, nil
+}
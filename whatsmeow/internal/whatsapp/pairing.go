@@ -0,0 +1,58 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.mau.fi/whatsmeow"
+)
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+
+// ValidE164 reports whether phone looks like a valid E.164 number
+func ValidE164(phone string) bool {
+	return e164Pattern.MatchString(phone)
+}
+
+// PairPhone starts (or resumes) instanceID's client without a QR code and asks
+// WhatsApp for an 8-character pairing code to be entered on the phone itself
+func (m *Manager) PairPhone(instanceID, phoneNumber string) (string, error) {
+	if !ValidE164(phoneNumber) {
+		return "", fmt.Errorf("phone number must be in E.164 format, e.g. +15551234567")
+	}
+
+	inst, err := m.getOrCreateInstance(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	inst.RLock()
+	paired := inst.Client.Store.ID != nil
+	inst.RUnlock()
+	if paired {
+		return "", fmt.Errorf("instance %s is already paired", instanceID)
+	}
+
+	if !inst.Client.IsConnected() {
+		if err := inst.Client.Connect(); err != nil {
+			return "", fmt.Errorf("failed to connect: %w", err)
+		}
+	}
+
+	code, err := inst.Client.PairPhone(context.Background(), phoneNumber, true, whatsmeow.PairClientChrome, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to request pairing code: %w", err)
+	}
+
+	inst.Lock()
+	inst.Status = "pairing"
+	inst.PairingCode = code
+	inst.Unlock()
+
+	m.broadcast(inst, "pairingCode", map[string]interface{}{
+		"pairingCode": code,
+	})
+
+	return code, nil
+}
@@ -0,0 +1,227 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	maxButtons  = 3
+	maxListRows = 10
+)
+
+// ContactCard is a vCard contact to send as a ContactMessage
+type ContactCard struct {
+	Name        string `json:"name"`
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+// Button is one quick-reply option on a ButtonsMessage
+type Button struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// ListRow is one selectable row inside a ListSection
+type ListRow struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// ListSection groups ListRows under a heading in a ListMessage
+type ListSection struct {
+	Title string    `json:"title"`
+	Rows  []ListRow `json:"rows"`
+}
+
+// QuotedMessage identifies the message a reply is quoting and carries enough of its
+// original content for the recipient's client to render a real quote preview, since
+// whatsmeow has no API to fetch an arbitrary past message's proto by ID
+type QuotedMessage struct {
+	MessageID   string
+	FromMe      bool
+	Participant string
+	Text        string // the quoted message's original text or caption
+}
+
+// buildContextInfo turns a QuotedMessage into the ContextInfo WhatsApp expects on a
+// reply, resolving participant to selfJID when the quoted message was sent by us
+func buildContextInfo(quoted QuotedMessage, selfJID string) *waE2E.ContextInfo {
+	participant := quoted.Participant
+	if quoted.FromMe && participant == "" {
+		participant = selfJID
+	}
+
+	return &waE2E.ContextInfo{
+		StanzaID:      proto.String(quoted.MessageID),
+		Participant:   proto.String(participant),
+		QuotedMessage: &waE2E.Message{Conversation: proto.String(quoted.Text)},
+	}
+}
+
+// SendLocationMessage sends a pin at (latitude, longitude) with an optional name/address
+func (m *Manager) SendLocationMessage(instanceID, to string, latitude, longitude float64, name, address string) (string, error) {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	msg := &waE2E.Message{
+		LocationMessage: &waE2E.LocationMessage{
+			DegreesLatitude:  proto.Float64(latitude),
+			DegreesLongitude: proto.Float64(longitude),
+			Name:             proto.String(name),
+			Address:          proto.String(address),
+		},
+	}
+
+	resp, err := inst.Client.SendMessage(context.Background(), jidFromPhone(to), msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send location message: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// SendContactMessage sends a vCard 3.0 contact card
+func (m *Manager) SendContactMessage(instanceID, to string, contact ContactCard) (string, error) {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	vcard := fmt.Sprintf(
+		"BEGIN:VCARD\nVERSION:3.0\nN:;%s;;;\nFN:%s\nTEL;type=CELL;waid=%s:+%s\nEND:VCARD",
+		contact.Name, contact.Name, contact.PhoneNumber, contact.PhoneNumber,
+	)
+
+	msg := &waE2E.Message{
+		ContactMessage: &waE2E.ContactMessage{
+			DisplayName: proto.String(contact.Name),
+			Vcard:       proto.String(vcard),
+		},
+	}
+
+	resp, err := inst.Client.SendMessage(context.Background(), jidFromPhone(to), msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send contact message: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// SendButtonsMessage sends text with up to 3 quick-reply buttons
+func (m *Manager) SendButtonsMessage(instanceID, to, text, footer string, buttons []Button) (string, error) {
+	if len(buttons) == 0 {
+		return "", fmt.Errorf("at least one button is required")
+	}
+	if len(buttons) > maxButtons {
+		return "", fmt.Errorf("at most %d buttons are supported", maxButtons)
+	}
+
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	waButtons := make([]*waE2E.ButtonsMessage_Button, 0, len(buttons))
+	for _, b := range buttons {
+		waButtons = append(waButtons, &waE2E.ButtonsMessage_Button{
+			ButtonID: proto.String(b.ID),
+			ButtonText: &waE2E.ButtonsMessage_Button_ButtonText{
+				DisplayText: proto.String(b.Text),
+			},
+			Type: waE2E.ButtonsMessage_Button_RESPONSE.Enum(),
+		})
+	}
+
+	msg := &waE2E.Message{
+		ButtonsMessage: &waE2E.ButtonsMessage{
+			ContentText: proto.String(text),
+			FooterText:  proto.String(footer),
+			HeaderType:  waE2E.ButtonsMessage_EMPTY.Enum(),
+			Buttons:     waButtons,
+		},
+	}
+
+	resp, err := inst.Client.SendMessage(context.Background(), jidFromPhone(to), msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send buttons message: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// SendListMessage sends a list of up to 10 rows grouped into sections, opened via buttonText
+func (m *Manager) SendListMessage(instanceID, to, title, text, footer, buttonText string, sections []ListSection) (string, error) {
+	rowCount := 0
+	for _, s := range sections {
+		rowCount += len(s.Rows)
+	}
+	if rowCount == 0 {
+		return "", fmt.Errorf("at least one row is required")
+	}
+	if rowCount > maxListRows {
+		return "", fmt.Errorf("at most %d rows are supported", maxListRows)
+	}
+
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	waSections := make([]*waE2E.ListMessage_Section, 0, len(sections))
+	for _, s := range sections {
+		rows := make([]*waE2E.ListMessage_Row, 0, len(s.Rows))
+		for _, row := range s.Rows {
+			rows = append(rows, &waE2E.ListMessage_Row{
+				RowID:       proto.String(row.ID),
+				Title:       proto.String(row.Title),
+				Description: proto.String(row.Description),
+			})
+		}
+		waSections = append(waSections, &waE2E.ListMessage_Section{
+			Title: proto.String(s.Title),
+			Rows:  rows,
+		})
+	}
+
+	msg := &waE2E.Message{
+		ListMessage: &waE2E.ListMessage{
+			Title:       proto.String(title),
+			Description: proto.String(text),
+			FooterText:  proto.String(footer),
+			ButtonText:  proto.String(buttonText),
+			ListType:    waE2E.ListMessage_SINGLE_SELECT.Enum(),
+			Sections:    waSections,
+		},
+	}
+
+	resp, err := inst.Client.SendMessage(context.Background(), jidFromPhone(to), msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send list message: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// SendReplyMessage sends a text message quoting an earlier message via its stanza ID
+func (m *Manager) SendReplyMessage(instanceID, to, text string, quoted QuotedMessage) (string, error) {
+	inst, err := m.resolveClient(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	msg := &waE2E.Message{
+		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:        proto.String(text),
+			ContextInfo: buildContextInfo(quoted, inst.Client.Store.ID.ToNonAD().String()),
+		},
+	}
+
+	resp, err := inst.Client.SendMessage(context.Background(), jidFromPhone(to), msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send reply message: %w", err)
+	}
+	return resp.ID, nil
+}
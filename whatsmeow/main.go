@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"whatsmeow-service/internal/api"
+	"whatsmeow-service/internal/config"
+	"whatsmeow-service/internal/history"
+	"whatsmeow-service/internal/media"
+	"whatsmeow-service/internal/webhook"
+	"whatsmeow-service/internal/whatsapp"
+)
+
+func main() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	cfg := config.Load()
+
+	manager, err := whatsapp.NewManager(cfg.DBPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize WhatsApp manager")
+	}
+
+	webhooks, err := webhook.NewDispatcher(cfg.WebhookStatePath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize webhook dispatcher")
+	}
+	manager.SetWebhookDispatcher(webhooks)
+
+	mediaStore, err := media.NewStore(cfg.MediaPath, cfg.MediaMaxSizeBytes, cfg.MediaTTL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize media store")
+	}
+	manager.SetMediaStore(mediaStore, cfg.MediaDownloadViewOnce)
+	go mediaStore.StartCleanup(nil)
+
+	historyStore, err := history.NewStore(cfg.HistoryDBPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize history store")
+	}
+	manager.SetHistoryStore(historyStore)
+
+	handlers := api.NewHandlers(manager, webhooks, mediaStore, historyStore, cfg.AllowedOrigins)
+	router := api.NewRouter(handlers, manager, cfg.AdminToken)
+
+	log.Info().Str("port", cfg.Port).Msg("Starting whatsmeow-service")
+	if err := http.ListenAndServe(":"+cfg.Port, router); err != nil {
+		log.Fatal().Err(err).Msg("Server stopped")
+	}
+}